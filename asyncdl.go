@@ -4,25 +4,41 @@ package asyncdl
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
+	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/rusq/dlog"
 	"github.com/rusq/fsadapter"
+	"golang.org/x/time/rate"
 )
 
 const (
 	// defNumWorkers is the default number of goroutines to spawn for
 	// concurrent download.
 	defNumWorkers = 12
+	// defMaxAttempts is the default number of attempts for a single
+	// download, i.e. no retries.
+	defMaxAttempts = 1
+	// defBaseBackoff is the default base backoff duration used by [Retry]
+	// when no value is given.
+	defBaseBackoff = 500 * time.Millisecond
 )
 
 // Download downloads files given the list of urls to the subdirectory within
@@ -49,17 +65,54 @@ type Manager struct {
 	// numWorkers is the number of download workers.
 	numWorkers int
 	// fetchFn is the function that is called to download each of the provided
-	// URLs.
+	// URLs.  It defaults to [Manager.dispatch], which routes to fetchers by
+	// URL scheme; tests may override it directly.
 	fetchFn fetchFunc
-	// if ignoreHTTPerr is false, the Download will terminate on any HTTP GET
-	// error.  If false, it will ignore the error and continue.
-	ignoreHTTPerr bool
+	// fetchers holds the registered [Fetcher] for each URL scheme, set with
+	// the [RegisterFetcher] option.
+	fetchers map[string]Fetcher
+	// if failFast is true, Download terminates as soon as any file fails.
+	// Otherwise every file is attempted and the failures, if any, are
+	// returned together as a [DownloadErrors] once the batch completes.  Set
+	// with the [FailFast] option.
+	failFast bool
 
 	// fsc is the base file system adapter, it points to a filesystem which
 	// we are free to create files or directories in.
 	fsc fsadapter.FSCloser
 	// isClosed indicates, that the close was called on fs adapter.
 	isClosed atomic.Bool
+
+	// httpClient is the client used to perform the requests, it can be
+	// overridden with the [HTTPClient] option.
+	httpClient *http.Client
+
+	// maxAttempts is the maximum number of attempts for a single download,
+	// set with the [Retry] option.  1 means no retries.
+	maxAttempts int
+	// baseBackoff is the base duration for the exponential backoff between
+	// retries, set with the [Retry] option.
+	baseBackoff time.Duration
+
+	// rateLimit is the number of requests per second allowed per host, 0
+	// disables rate limiting.  Set with the [RateLimit] option.
+	rateLimit int
+	// rateBurst is the burst size of the per-host token bucket.
+	rateBurst int
+	// limiters holds the per-host token buckets, lazily created.
+	limitersMu sync.Mutex
+	limiters   map[string]*rate.Limiter
+
+	// resume enables resuming of interrupted downloads via HTTP Range
+	// requests, set with the [Resume] option.
+	resume bool
+
+	// progress receives per-file and aggregate progress notifications, set
+	// with the [WithProgress] option.  Nil disables progress reporting.
+	progress Progress
+	// prefetchSizes enables an upfront HEAD pass to discover file sizes,
+	// set with the [PrefetchSizes] option.
+	prefetchSizes bool
 }
 
 // Option is the download manager option.
@@ -77,13 +130,155 @@ func NumWorkers(n int) Option {
 }
 
 // IgnoreHTTPErrors allows to ignore HTTP errors (enabled by default)
+//
+// Deprecated: use [FailFast] instead, which replaced this option's
+// terminate-on-first-error behaviour.  IgnoreHTTPErrors(false) is equivalent
+// to FailFast(true); failures are no longer silently discarded when enabled
+// — they are collected into the [DownloadErrors] returned by
+// [Manager.Download].
 func IgnoreHTTPErrors(isEnabled bool) Option {
+	return FailFast(!isEnabled)
+}
+
+// FailFast controls whether Download stops at the first failed file
+// (enabled) or keeps going and reports every failure once the batch
+// completes (the default).  In both cases, failures are collected into a
+// [DownloadErrors] returned by [Manager.Download]; use errors.As to inspect
+// individual [FetchError], [ChecksumError], or [WriteError] values and
+// decide which URLs to retry.
+func FailFast(enabled bool) Option {
+	return func(m *Manager) {
+		m.failFast = enabled
+	}
+}
+
+// HTTPClient allows to set the HTTP client used for the requests, instead of
+// relying on [http.DefaultClient].  This is useful for setting timeouts,
+// custom transports, or authentication.
+func HTTPClient(client *http.Client) Option {
+	return func(m *Manager) {
+		if client == nil {
+			client = http.DefaultClient
+		}
+		m.httpClient = client
+	}
+}
+
+// RateLimit restricts the number of requests per second issued against any
+// single host to perHost, allowing bursts of up to burst requests.  Limits
+// are applied independently for each host, so downloads across different
+// hosts are not affected by each other.  A perHost of 0 or less disables
+// rate limiting (the default).  A burst of less than 1 is treated as 1,
+// since a limiter with a zero burst can never let a single request through.
+func RateLimit(perHost int, burst int) Option {
+	return func(m *Manager) {
+		if burst < 1 {
+			burst = 1
+		}
+		m.rateLimit = perHost
+		m.rateBurst = burst
+	}
+}
+
+// Retry enables retrying of failed downloads up to maxAttempts times,
+// using an exponential backoff with jitter starting at baseBackoff.
+// Network errors and 5xx responses are retried, 4xx responses (other than
+// 408 and 429) are treated as terminal.  A maxAttempts of 1 or less
+// disables retries (the default).
+func Retry(maxAttempts int, baseBackoff time.Duration) Option {
+	return func(m *Manager) {
+		m.maxAttempts = maxAttempts
+		if baseBackoff <= 0 {
+			baseBackoff = defBaseBackoff
+		}
+		m.baseBackoff = baseBackoff
+	}
+}
+
+// Resume enables resuming of interrupted downloads using HTTP Range
+// requests (RFC 7233).  When a previous attempt at a given file was
+// interrupted, the next call to [Manager.Download] continues from where it
+// left off instead of starting over, provided the server supports range
+// requests and the resource has not changed in the meantime.
+func Resume(enabled bool) Option {
+	return func(m *Manager) {
+		m.resume = enabled
+	}
+}
+
+// WithProgress installs p as the observer of per-file and aggregate
+// download progress.  See [Progress] for details, and [NewTerminalProgress]
+// for the default terminal implementation.
+func WithProgress(p Progress) Option {
 	return func(m *Manager) {
-		m.ignoreHTTPerr = isEnabled
+		m.progress = p
 	}
 }
 
-type fetchFunc func(ctx context.Context, fsa fsadapter.FS, dir string, name string, uri string) error
+// RegisterFetcher installs f as the [Fetcher] used for every URL scheme it
+// reports via [Fetcher.Scheme], replacing any Fetcher (including a built-in
+// one) already registered for them.  This is how to add support for a new
+// protocol, or to wrap an existing one — e.g. a caching Fetcher that checks
+// a local content-addressed store before delegating to the built-in HTTP
+// Fetcher for a miss.
+func RegisterFetcher(f Fetcher) Option {
+	return func(m *Manager) {
+		for _, scheme := range f.Scheme() {
+			m.fetchers[scheme] = f
+		}
+	}
+}
+
+// PrefetchSizes enables an upfront, parallel HEAD request pass over every
+// URL so that expected file sizes are known before the download pipeline
+// starts, letting a [Progress] report accurate totals and ETAs.  Disabled
+// by default, since it doubles the number of requests and not every server
+// answers HEAD correctly.
+func PrefetchSizes(enabled bool) Option {
+	return func(m *Manager) {
+		m.prefetchSizes = enabled
+	}
+}
+
+// FetchOptions bundles the cross-cutting concerns every [Fetcher] may need,
+// so that supporting one more doesn't mean growing the interface again.
+type FetchOptions struct {
+	Client   *http.Client
+	Resume   bool
+	Progress Progress
+	// Size is the expected size of the file, discovered by [PrefetchSizes],
+	// or -1 if unknown.
+	Size int64
+	// SHA256 is the expected SHA256 digest of the file, or empty to skip
+	// verification.
+	SHA256 string
+	// Headers carries extra request headers to send.
+	Headers map[string]string
+}
+
+// Fetcher downloads a single file into a file system adapter.  Manager
+// dispatches to a Fetcher based on the URL scheme of the file being
+// downloaded; register one with [RegisterFetcher].
+type Fetcher interface {
+	// Fetch downloads uri into dir/name within fsa, according to opts.
+	Fetch(ctx context.Context, fsa fsadapter.FS, dir, name, uri string, opts FetchOptions) error
+	// Scheme returns the URL schemes (e.g. "http", "https") this Fetcher
+	// handles.
+	Scheme() []string
+}
+
+// httpFetcher is the built-in [Fetcher] for http:// and https:// URLs.
+type httpFetcher struct{}
+
+func (httpFetcher) Fetch(ctx context.Context, fsa fsadapter.FS, dir, name, uri string, opts FetchOptions) error {
+	return get(ctx, fsa, dir, name, uri, opts)
+}
+
+func (httpFetcher) Scheme() []string { return []string{"http", "https"} }
+
+var _ Fetcher = httpFetcher{}
+
+type fetchFunc func(ctx context.Context, fsa fsadapter.FS, dir string, name string, uri string, opts FetchOptions) error
 
 // New creates a new download Manager.
 func New(fsa fsadapter.FS, opts ...Option) *Manager {
@@ -92,11 +287,19 @@ func New(fsa fsadapter.FS, opts ...Option) *Manager {
 
 func newMgr(fsc fsadapter.FSCloser, opts ...Option) *Manager {
 	m := &Manager{
-		numWorkers:    defNumWorkers,
-		fetchFn:       get,
-		fsc:           fsc,
-		ignoreHTTPerr: true,
+		numWorkers:  defNumWorkers,
+		fsc:         fsc,
+		httpClient:  http.DefaultClient,
+		maxAttempts: defMaxAttempts,
+		baseBackoff: defBaseBackoff,
+		fetchers: map[string]Fetcher{
+			"http":  httpFetcher{},
+			"https": httpFetcher{},
+			"file":  fileFetcher{},
+			"ftp":   ftpFetcher{},
+		},
 	}
+	m.fetchFn = m.dispatch
 	for _, opt := range opts {
 		opt(m)
 	}
@@ -143,12 +346,33 @@ func (m *Manager) Close() error {
 type request struct {
 	filename string
 	url      string
+	// size is the expected size of the file in bytes, or -1 if unknown.
+	// Populated by the [PrefetchSizes] option.
+	size int64
+	// sha256 is the expected SHA256 digest of the file, lower-case hex, or
+	// empty if the file should not be verified.  Populated from an [Entry]
+	// by [Manager.DownloadManifest].
+	sha256 string
+	// headers carries extra request headers to send, populated from an
+	// [Entry] by [Manager.DownloadManifest].
+	headers map[string]string
 }
 
 // Download downloads the files and saves them to the dir directory within the
 // file system adapter fsa. It spawns numWorker goroutines for getting the
 // files. It will call fetchFn for each url.
 func (m *Manager) Download(ctx context.Context, dir string, urls []string) error {
+	reqs, err := parseURLs(urls)
+	if err != nil {
+		return fmt.Errorf("error parsing urls: %w", err)
+	}
+	return m.run(ctx, dir, reqs)
+}
+
+// run drives the async download pipeline for reqs, saving the files to the
+// dir directory within the file system adapter fsa.  It is shared by
+// [Manager.Download] and [Manager.DownloadManifest].
+func (m *Manager) run(ctx context.Context, dir string, reqs []request) error {
 	if m.isClosed.Load() {
 		return errors.New("manager is closed")
 	}
@@ -158,9 +382,8 @@ func (m *Manager) Download(ctx context.Context, dir string, urls []string) error
 
 	lg := dlog.FromContext(ctx)
 
-	reqs, err := parseURLs(urls)
-	if err != nil {
-		return fmt.Errorf("error parsing urls: %w", err)
+	if m.prefetchSizes {
+		m.fetchSizes(ctx, reqs)
 	}
 
 	var (
@@ -197,29 +420,86 @@ func (m *Manager) Download(ctx context.Context, dir string, urls []string) error
 		close(resultC)
 	}()
 
-	// 4. Result processor, receives download results and logs any errors that
-	//    may have occurred.
+	// 4. Result processor, receives download results, logs any errors that
+	//    may have occurred, and collects them to return to the caller.
 	var (
-		total = len(urls)
+		total = len(reqs)
 		count = 0
+		errs  DownloadErrors
 	)
 	for res := range resultC {
+		if m.progress != nil {
+			m.progress.Finish(res.filename, res.err)
+		}
 		if res.err != nil {
 			if errors.Is(res.err, context.Canceled) {
 				return res.err
 			}
-			if !m.ignoreHTTPerr {
-				return fmt.Errorf("failed: %q: %w", res.filename, res.err)
-			}
 			lg.Printf("failed: %q: %s", res.filename, res.err)
+			errs = append(errs, res.err)
+			if m.failFast {
+				return errs
+			}
 		}
 		count++
 		lg.Printf("downloaded % 5d/%d %q", count, total, res.filename)
 	}
 
+	if len(errs) > 0 {
+		return errs
+	}
 	return nil
 }
 
+// fetchSizes issues a HEAD request for each of reqs whose size isn't
+// already known, populating req.size.  It never overrides a size already
+// set (e.g. from a manifest [Entry.Size]); it is best-effort, so a failed
+// or missing HEAD response simply leaves the size unknown (-1).
+func (m *Manager) fetchSizes(ctx context.Context, reqs []request) {
+	client := m.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	sem := make(chan struct{}, m.numWorkers)
+	var wg sync.WaitGroup
+	for i := range reqs {
+		if reqs[i].size >= 0 {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			reqs[i].size = headSize(ctx, client, reqs[i].url, reqs[i].headers)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// headSize issues a HEAD request for uri, with headers attached, and
+// returns the reported Content-Length, or -1 if it could not be
+// determined.
+func headSize(ctx context.Context, client *http.Client, uri string, headers map[string]string) int64 {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, uri, nil)
+	if err != nil {
+		return -1
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return -1
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || resp.ContentLength < 0 {
+		return -1
+	}
+	return resp.ContentLength
+}
+
 type result struct {
 	filename string
 	err      error
@@ -238,21 +518,192 @@ func (m *Manager) worker(ctx context.Context, dir string, requestC <-chan reques
 			if !more {
 				return
 			}
-			err := m.fetchFn(ctx, m.fsc, dir, req.filename, req.url)
+			err := m.fetch(ctx, dir, req)
 			resultC <- result{filename: req.filename, err: err}
 		}
 	}
 }
 
+// fetch runs fetchFn for req, gating on the per-host rate limiter and
+// retrying transient errors, as configured by the [RateLimit] and [Retry]
+// options.
+func (m *Manager) fetch(ctx context.Context, dir string, req request) error {
+	attempts := m.maxAttempts
+	if attempts < 1 {
+		attempts = defMaxAttempts
+	}
+	client := m.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var progress Progress
+	if m.progress != nil {
+		progress = &startOnceProgress{Progress: m.progress}
+	}
+	opts := FetchOptions{
+		Client:   client,
+		Resume:   m.resume,
+		Progress: progress,
+		Size:     req.size,
+		SHA256:   req.sha256,
+		Headers:  req.headers,
+	}
+
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if lim := m.hostLimiter(req.url); lim != nil {
+			if err := lim.Wait(ctx); err != nil {
+				return err
+			}
+		}
+
+		err := m.fetchFn(ctx, m.fsc, dir, req.filename, req.url, opts)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+
+		if attempt == attempts || !isRetryable(err) {
+			return wrapFetchError(req, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryDelay(err, m.baseBackoff, attempt)):
+		}
+	}
+	return wrapFetchError(req, lastErr)
+}
+
+// wrapFetchError records req's URL and filename, plus the HTTP status code
+// if err is (or wraps) an [httpStatusError], onto a [FetchError].
+func wrapFetchError(req request, err error) error {
+	var statusErr *httpStatusError
+	code := 0
+	if errors.As(err, &statusErr) {
+		code = statusErr.Code
+	}
+	return &FetchError{URL: req.url, Filename: req.filename, StatusCode: code, Err: err}
+}
+
+// dispatch is the default fetchFn: it routes uri to the [Fetcher]
+// registered for its URL scheme.  See [RegisterFetcher].
+func (m *Manager) dispatch(ctx context.Context, fsa fsadapter.FS, dir, name, uri string, opts FetchOptions) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	f, ok := m.fetchers[u.Scheme]
+	if !ok {
+		return fmt.Errorf("no fetcher registered for scheme %q", u.Scheme)
+	}
+	return f.Fetch(ctx, fsa, dir, name, uri, opts)
+}
+
+// hostLimiter returns the token bucket limiter for the host of uri, creating
+// it on first use.  It returns nil if rate limiting is disabled.
+func (m *Manager) hostLimiter(uri string) *rate.Limiter {
+	if m.rateLimit <= 0 {
+		return nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil
+	}
+
+	m.limitersMu.Lock()
+	defer m.limitersMu.Unlock()
+	if m.limiters == nil {
+		m.limiters = make(map[string]*rate.Limiter)
+	}
+	lim, ok := m.limiters[u.Host]
+	if !ok {
+		lim = rate.NewLimiter(rate.Limit(m.rateLimit), m.rateBurst)
+		m.limiters[u.Host] = lim
+	}
+	return lim
+}
+
+// isRetryable reports whether err represents a transient failure that is
+// worth retrying: network errors and 5xx or 408/429 HTTP responses.  Other
+// 4xx responses are considered terminal.
+func isRetryable(err error) bool {
+	var statusErr *httpStatusError
+	if !errors.As(err, &statusErr) {
+		// Not an HTTP status error, i.e. a network-level failure.
+		return true
+	}
+	if statusErr.Code == http.StatusRequestTimeout || statusErr.Code == http.StatusTooManyRequests {
+		return true
+	}
+	return statusErr.Code >= 500
+}
+
+// retryDelay calculates how long to wait before the next attempt.  It
+// honours the Retry-After value reported by the server, if any, otherwise
+// it falls back to an exponential backoff with jitter.
+func retryDelay(err error, base time.Duration, attempt int) time.Duration {
+	var statusErr *httpStatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+		return statusErr.RetryAfter
+	}
+	backoff := base << (attempt - 1)
+	jitter := time.Duration(rand.Int63n(int64(base) + 1))
+	return backoff + jitter
+}
+
+// httpStatusError is returned by get when the server responds with a
+// non-2xx status code.
+type httpStatusError struct {
+	Code       int
+	Status     string
+	RetryAfter time.Duration
+}
+
+func (e *httpStatusError) Error() string {
+	return fmt.Sprintf("invalid server status code: %d (%s)", e.Code, e.Status)
+}
+
+// parseRetryAfter parses the Retry-After response header, which is either a
+// number of seconds or an HTTP date, per RFC 7231 section 7.1.3.  It returns
+// 0 if the header is absent or unparseable.
+func parseRetryAfter(h string) time.Duration {
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil {
+		return time.Duration(secs) * time.Second
+	}
+	if when, err := http.ParseTime(h); err == nil {
+		return time.Until(when)
+	}
+	return 0
+}
+
 // get downloads one file from the uri into the dir/filename within the
-// filesystem wrapped with the fsa.
-func get(ctx context.Context, fsa fsadapter.FS, dir string, filename, uri string) error {
+// filesystem wrapped with the fsa, according to opts.  If opts.Resume is
+// set, it is continued via a Range request should a previous, interrupted
+// attempt have left a partial file behind.
+func get(ctx context.Context, fsa fsadapter.FS, dir string, filename, uri string, opts FetchOptions) error {
+	if !opts.Resume {
+		return getFresh(ctx, fsa, dir, filename, uri, opts)
+	}
+	return getResumable(ctx, fsa, dir, filename, uri, opts)
+}
+
+// getFresh issues a plain GET and streams the response straight into the
+// destination file, with no regard for any previous attempt.
+func getFresh(ctx context.Context, fsa fsadapter.FS, dir string, filename, uri string, opts FetchOptions) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
 		return err
 	}
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := opts.Client.Do(req)
 	if err != nil {
 		return err
 	}
@@ -261,18 +712,281 @@ func get(ctx context.Context, fsa fsadapter.FS, dir string, filename, uri string
 	fp := filepath.Join(dir, filename)
 	wc, err := fsa.Create(fp)
 	if err != nil {
-		return fmt.Errorf("error creating the file at path %q: %w", fp, err)
+		return &WriteError{Filename: filename, Err: err}
 	}
 	defer wc.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("invalid server status code: %d (%s)", resp.StatusCode, resp.Status)
+		return &httpStatusError{
+			Code:       resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
+
+	body := progressBody(filename, expectedSize(opts.Size, resp.ContentLength), opts.Progress, resp.Body)
+
+	var hasher hash.Hash
+	dst := io.Writer(wc)
+	if opts.SHA256 != "" {
+		hasher = sha256.New()
+		dst = io.MultiWriter(wc, hasher)
+	}
+
+	if _, err := io.Copy(dst, body); err != nil {
+		return err
+	}
+
+	if hasher != nil {
+		if got := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(got, opts.SHA256) {
+			wc.Close()
+			// fsadapter.FS has no delete, so zero out the corrupt file
+			// rather than leave bad data behind under its name.
+			_ = fsa.WriteFile(fp, nil, 0o644)
+			return &ChecksumError{Filename: filename, Want: opts.SHA256, Got: got}
+		}
+	}
+
+	return nil
+}
+
+// expectedSize picks the best known total size for a download: the
+// prefetched size if there is one, falling back to the size reported by
+// the response itself, or -1 if neither is known.
+func expectedSize(prefetched, contentLength int64) int64 {
+	if prefetched >= 0 {
+		return prefetched
+	}
+	return contentLength
+}
+
+// progressBody wraps r so that reads are reported to p, and signals the
+// start of the download to p before returning.  p may be nil, in which
+// case r is returned unchanged.
+func progressBody(filename string, size int64, p Progress, r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	p.Start(filename, size)
+	return &progressReader{r: r, filename: filename, progress: p}
+}
+
+// partMeta is the sidecar recorded alongside a partially downloaded file so
+// that a later attempt can tell whether the remote resource is still the
+// one it started downloading.
+type partMeta struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// getResumable downloads uri into dir/filename, resuming from a partial
+// file left behind by a previous, interrupted attempt.
+//
+// [fsadapter.FS] only exposes Create (which always truncates) and
+// WriteFile, it has no way to report the size of an existing file or to
+// open one for append.  To work around that, the in-progress download is
+// staged in a local temporary directory — which we fully control — and
+// only copied into fsa once it is complete.  A "<name>.part.meta" sidecar
+// next to the staged file records the ETag/Last-Modified of the response,
+// so a stale or changed resource is detected and restarted rather than
+// silently corrupted.
+func getResumable(ctx context.Context, fsa fsadapter.FS, dir string, filename, uri string, opts FetchOptions) error {
+	partPath, metaPath, err := resumePaths(dir, filename)
+	if err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+
+	var offset int64
+	if fi, err := os.Stat(partPath); err == nil {
+		offset = fi.Size()
 	}
 
-	if _, err := io.Copy(wc, resp.Body); err != nil {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
 		return err
 	}
+	for k, v := range opts.Headers {
+		req.Header.Set(k, v)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+		if meta, ok := readPartMeta(metaPath); ok {
+			if meta.ETag != "" {
+				req.Header.Set("If-Range", meta.ETag)
+			} else if meta.LastModified != "" {
+				req.Header.Set("If-Range", meta.LastModified)
+			}
+		}
+	}
+
+	resp, err := opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// Server doesn't support range requests, or chose to ignore ours:
+		// restart from scratch.
+		offset = 0
+	case http.StatusPartialContent:
+		if !contentRangeMatches(resp.Header.Get("Content-Range"), offset) {
+			// The server answered a range we didn't ask for; play it safe
+			// and restart rather than risk a corrupt file.
+			offset = 0
+		}
+	default:
+		return &httpStatusError{
+			Code:       resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+		}
+	}
 
+	if err := writePartMeta(metaPath, partMeta{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}); err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+
+	flags := os.O_CREATE | os.O_WRONLY
+	if offset > 0 {
+		flags |= os.O_APPEND
+	} else {
+		flags |= os.O_TRUNC
+	}
+	pf, err := os.OpenFile(partPath, flags, 0o644)
+	if err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+
+	size := expectedSize(opts.Size, -1)
+	if resp.ContentLength >= 0 {
+		size = offset + resp.ContentLength
+	}
+	body := progressBody(filename, size, opts.Progress, resp.Body)
+
+	_, copyErr := io.Copy(pf, body)
+	closeErr := pf.Close()
+	if copyErr != nil {
+		// Whatever made it to disk, plus the sidecar written above, is
+		// enough for the next attempt to pick up where this one left off.
+		return copyErr
+	}
+	if closeErr != nil {
+		return closeErr
+	}
+
+	if opts.SHA256 != "" {
+		got, err := sha256File(partPath)
+		if err != nil {
+			return fmt.Errorf("resume: %w", err)
+		}
+		if !strings.EqualFold(got, opts.SHA256) {
+			os.Remove(partPath)
+			os.Remove(metaPath)
+			return &ChecksumError{Filename: filename, Want: opts.SHA256, Got: got}
+		}
+	}
+
+	if err := copyIntoFS(fsa, dir, filename, partPath); err != nil {
+		return err
+	}
+	os.Remove(partPath)
+	os.Remove(metaPath)
+	return nil
+}
+
+// sha256File hashes the file at path in its entirety.
+func sha256File(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// resumeRoot is the local directory used to stage in-progress downloads.
+func resumeRoot() string {
+	return filepath.Join(os.TempDir(), "asyncdl-resume")
+}
+
+// resumePaths returns the staging path for the partial file and its
+// metadata sidecar for dir/filename, creating the staging directory if
+// necessary.
+func resumePaths(dir, filename string) (partPath, metaPath string, err error) {
+	stageDir := filepath.Join(resumeRoot(), dir)
+	if err := os.MkdirAll(stageDir, 0o755); err != nil {
+		return "", "", err
+	}
+	partPath = filepath.Join(stageDir, filename+".part")
+	metaPath = filepath.Join(stageDir, filename+".part.meta")
+	return partPath, metaPath, nil
+}
+
+// readPartMeta reads and parses the sidecar at path, if any.
+func readPartMeta(path string) (partMeta, bool) {
+	var meta partMeta
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return meta, false
+	}
+	if err := json.Unmarshal(b, &meta); err != nil {
+		return meta, false
+	}
+	return meta, true
+}
+
+// writePartMeta writes the sidecar at path.
+func writePartMeta(path string, meta partMeta) error {
+	b, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+// contentRangeMatches reports whether the Content-Range response header
+// confirms that the server actually resumed at offset, per RFC 7233
+// section 4.2 ("Content-Range: bytes first-last/complete-length").
+func contentRangeMatches(header string, offset int64) bool {
+	if header == "" {
+		return false
+	}
+	var start, end, total int64
+	if _, err := fmt.Sscanf(header, "bytes %d-%d/%d", &start, &end, &total); err != nil {
+		return false
+	}
+	return start == offset
+}
+
+// copyIntoFS copies the staged file at srcPath into dir/filename within
+// fsa, as the final step of a completed resumable download.
+func copyIntoFS(fsa fsadapter.FS, dir, filename, srcPath string) error {
+	src, err := os.Open(srcPath)
+	if err != nil {
+		return fmt.Errorf("resume: %w", err)
+	}
+	defer src.Close()
+
+	fp := filepath.Join(dir, filename)
+	wc, err := fsa.Create(fp)
+	if err != nil {
+		return &WriteError{Filename: filename, Err: err}
+	}
+	defer wc.Close()
+
+	if _, err := io.Copy(wc, src); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -306,7 +1020,7 @@ func parseURLs(urls []string) ([]request, error) {
 		if err != nil {
 			return nil, err
 		}
-		reqs = append(reqs, request{filename: filename, url: uri})
+		reqs = append(reqs, request{filename: filename, url: uri, size: -1})
 	}
 	return reqs, nil
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"math/rand"
 	"net/http"
@@ -11,7 +12,9 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -83,7 +86,7 @@ func Test_get(t *testing.T) {
 				t.Fatalf("failed to create test dir: %s", err)
 			}
 
-			if err := get(tt.args.ctx, http.DefaultClient, fsa, tt.args.dir, tt.args.name, server.URL); (err != nil) != tt.wantErr {
+			if err := get(tt.args.ctx, fsa, tt.args.dir, tt.args.name, server.URL, FetchOptions{Client: http.DefaultClient, Size: -1}); (err != nil) != tt.wantErr {
 				t.Errorf("fetch() error = %v, wantErr %v", err, tt.wantErr)
 			}
 
@@ -106,6 +109,118 @@ func Test_get(t *testing.T) {
 	}
 }
 
+func Test_getResumable(t *testing.T) {
+	const full = "the quick brown fox jumps over the lazy dog"
+
+	t.Run("resumes a partial download", func(t *testing.T) {
+		var rangeSeen string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rangeSeen = r.Header.Get("Range")
+			w.Header().Set("ETag", `"etag"`)
+			if rangeSeen == "" {
+				w.WriteHeader(http.StatusOK)
+				io.WriteString(w, full)
+				return
+			}
+			w.Header().Set("Content-Range", fmt.Sprintf("bytes 10-%d/%d", len(full)-1, len(full)))
+			w.WriteHeader(http.StatusPartialContent)
+			io.WriteString(w, full[10:])
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		fsa, err := fsadapter.New(dir)
+		if err != nil {
+			t.Fatalf("failed to create test dir: %s", err)
+		}
+
+		partPath, metaPath, err := resumePaths("test", "file")
+		if err != nil {
+			t.Fatalf("resumePaths: %s", err)
+		}
+		defer os.Remove(partPath)
+		defer os.Remove(metaPath)
+		if err := os.WriteFile(partPath, []byte(full[:10]), 0o644); err != nil {
+			t.Fatalf("seeding partial file: %s", err)
+		}
+
+		if err := get(context.Background(), fsa, "test", "file", server.URL, FetchOptions{Client: http.DefaultClient, Resume: true, Size: -1}); err != nil {
+			t.Fatalf("get() error = %s", err)
+		}
+		if rangeSeen != "bytes=10-" {
+			t.Errorf("Range header = %q, want %q", rangeSeen, "bytes=10-")
+		}
+
+		got, err := os.ReadFile(filepath.Join(dir, "test", "file"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != full {
+			t.Errorf("file contents = %q, want %q", got, full)
+		}
+		if _, err := os.Stat(partPath); !os.IsNotExist(err) {
+			t.Errorf("staged part file was not cleaned up: err=%v", err)
+		}
+	})
+
+	t.Run("restarts when server ignores the range", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			io.WriteString(w, full)
+		}))
+		defer server.Close()
+
+		dir := t.TempDir()
+		fsa, err := fsadapter.New(dir)
+		if err != nil {
+			t.Fatalf("failed to create test dir: %s", err)
+		}
+
+		partPath, metaPath, err := resumePaths("test", "file")
+		if err != nil {
+			t.Fatalf("resumePaths: %s", err)
+		}
+		defer os.Remove(partPath)
+		defer os.Remove(metaPath)
+		if err := os.WriteFile(partPath, []byte("stale data"), 0o644); err != nil {
+			t.Fatalf("seeding partial file: %s", err)
+		}
+
+		if err := get(context.Background(), fsa, "test", "file", server.URL, FetchOptions{Client: http.DefaultClient, Resume: true, Size: -1}); err != nil {
+			t.Fatalf("get() error = %s", err)
+		}
+
+		got, err := os.ReadFile(filepath.Join(dir, "test", "file"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != full {
+			t.Errorf("file contents = %q, want %q", got, full)
+		}
+	})
+}
+
+func Test_contentRangeMatches(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		offset int64
+		want   bool
+	}{
+		{"matching offset", "bytes 10-43/44", 10, true},
+		{"mismatched offset", "bytes 0-43/44", 10, false},
+		{"empty header", "", 10, false},
+		{"malformed header", "bytes weird", 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := contentRangeMatches(tt.header, tt.offset); got != tt.want {
+				t.Errorf("contentRangeMatches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func testRequestC(requests []request, wantClosed bool) <-chan request {
 	ch := make(chan request)
 	go func() {
@@ -137,9 +252,9 @@ func Test_worker(t *testing.T) {
 			args{
 				ctx:      context.Background(),
 				dir:      "",
-				requestC: testRequestC([]request{{"test", "passed"}}, true),
+				requestC: testRequestC([]request{{filename: "test", url: "passed", size: -1}}, true),
 			},
-			func(_ context.Context, _ *http.Client, _ fsadapter.FS, _ string, _ string, _ string) error {
+			func(_ context.Context, _ fsadapter.FS, _ string, _ string, _ string, _ FetchOptions) error {
 				return nil
 			},
 			[]result{
@@ -153,7 +268,7 @@ func Test_worker(t *testing.T) {
 				dir:      "",
 				requestC: testRequestC([]request{}, false),
 			},
-			func(_ context.Context, _ *http.Client, _ fsadapter.FS, _ string, _ string, _ string) error {
+			func(_ context.Context, _ fsadapter.FS, _ string, _ string, _ string, _ FetchOptions) error {
 				return nil
 			},
 			[]result{
@@ -164,12 +279,14 @@ func Test_worker(t *testing.T) {
 			"fetch error",
 			args{
 				ctx:      context.Background(),
-				requestC: testRequestC([]request{{"test", "passed"}}, true),
+				requestC: testRequestC([]request{{filename: "test", url: "passed", size: -1}}, true),
 			},
-			func(_ context.Context, _ *http.Client, _ fsadapter.FS, _ string, _ string, _ string) error {
+			func(_ context.Context, _ fsadapter.FS, _ string, _ string, _ string, _ FetchOptions) error {
 				return io.EOF
 			},
 			[]result{
+				// m.fetch wraps fetchFn's error in a *FetchError, so this is
+				// checked separately below rather than via reflect.DeepEqual.
 				{filename: "test", err: io.EOF},
 			},
 		},
@@ -195,6 +312,12 @@ func Test_worker(t *testing.T) {
 			for r := range resultC {
 				results = append(results, r)
 			}
+			if tt.name == "fetch error" {
+				if len(results) != 1 || results[0].filename != "test" || !errors.Is(results[0].err, io.EOF) {
+					t.Errorf("results mismatch: want a FetchError wrapping io.EOF, got=%v", results)
+				}
+				return
+			}
 			if !reflect.DeepEqual(results, tt.wantResult) {
 				t.Errorf("results mismatch:\n\twant=%v\n\tgot =%v", tt.wantResult, results)
 			}
@@ -212,7 +335,7 @@ func Test_fetch(t *testing.T) {
 		var gotMu sync.Mutex
 		m := Manager{
 			fsc: fsa,
-			fetchFn: func(_ context.Context, _ *http.Client, _ fsadapter.FS, _ string, filename string, uri string) error {
+			fetchFn: func(_ context.Context, _ fsadapter.FS, _ string, filename string, uri string, _ FetchOptions) error {
 				gotMu.Lock()
 				got[filename] = uri
 				gotMu.Unlock()
@@ -241,6 +364,81 @@ func Test_fetch(t *testing.T) {
 	})
 }
 
+func Test_headSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		length int64
+		want   int64
+	}{
+		{"known size", http.StatusOK, 42, 42},
+		{"not found", http.StatusNotFound, 0, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodHead {
+					t.Errorf("method = %s, want HEAD", r.Method)
+				}
+				w.Header().Set("Content-Length", strconv.FormatInt(tt.length, 10))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			if got := headSize(context.Background(), http.DefaultClient, server.URL, nil); got != tt.want {
+				t.Errorf("headSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_headSize_Headers verifies that headSize sends the request's
+// headers, so a HEAD against an auth-walled URL can succeed just like the
+// GET that follows it.
+func Test_headSize_Headers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Length", "42")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	got := headSize(context.Background(), http.DefaultClient, server.URL, map[string]string{"Authorization": "Bearer secret"})
+	if got != 42 {
+		t.Errorf("headSize() = %d, want 42", got)
+	}
+}
+
+// Test_fetchSizes_PreservesKnownSize verifies that PrefetchSizes never
+// overrides a size already known (e.g. from a manifest Entry.Size), even
+// when the HEAD response reports a different value.
+func Test_fetchSizes_PreservesKnownSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	fsa, _ := fsadapter.New(t.TempDir())
+	m := New(fsa)
+
+	reqs := []request{
+		{filename: "known", url: server.URL, size: 999999},
+		{filename: "unknown", url: server.URL, size: -1},
+	}
+	m.fetchSizes(context.Background(), reqs)
+
+	if reqs[0].size != 999999 {
+		t.Errorf("known size = %d, want 999999 (must not be overridden)", reqs[0].size)
+	}
+	if reqs[1].size != 1 {
+		t.Errorf("unknown size = %d, want 1 (filled in from HEAD)", reqs[1].size)
+	}
+}
+
 func generateURLs(n int) (ret []string) {
 	ret = make([]string, n)
 	for i := 0; i < n; i++ {
@@ -321,6 +519,184 @@ func Test_basename(t *testing.T) {
 	}
 }
 
+func Test_isRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network error", io.ErrUnexpectedEOF, true},
+		{"408 request timeout", &httpStatusError{Code: http.StatusRequestTimeout}, true},
+		{"429 too many requests", &httpStatusError{Code: http.StatusTooManyRequests}, true},
+		{"500 internal server error", &httpStatusError{Code: http.StatusInternalServerError}, true},
+		{"503 service unavailable", &httpStatusError{Code: http.StatusServiceUnavailable}, true},
+		{"404 not found", &httpStatusError{Code: http.StatusNotFound}, false},
+		{"403 forbidden", &httpStatusError{Code: http.StatusForbidden}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_retryDelay(t *testing.T) {
+	const base = 10 * time.Millisecond
+
+	t.Run("honours Retry-After", func(t *testing.T) {
+		err := &httpStatusError{Code: http.StatusTooManyRequests, RetryAfter: 5 * time.Second}
+		if got := retryDelay(err, base, 3); got != 5*time.Second {
+			t.Errorf("retryDelay() = %s, want %s", got, 5*time.Second)
+		}
+	})
+
+	t.Run("exponential backoff with jitter when no Retry-After", func(t *testing.T) {
+		err := errors.New("network blip")
+		for attempt := 1; attempt <= 4; attempt++ {
+			backoff := base << (attempt - 1)
+			got := retryDelay(err, base, attempt)
+			if got < backoff || got > backoff+base {
+				t.Errorf("retryDelay(attempt=%d) = %s, want in [%s, %s]", attempt, got, backoff, backoff+base)
+			}
+		}
+	})
+}
+
+// Test_fetch_Retry drives a 429 response with Retry-After through
+// Manager.fetch and checks that it waits the advertised delay and retries
+// until the server starts answering with 200.
+func Test_fetch_Retry(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		if n < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	fsa, _ := fsadapter.New(t.TempDir())
+	m := New(fsa, Retry(5, time.Millisecond))
+
+	err := m.fetch(context.Background(), "", request{filename: "f", url: server.URL, size: -1})
+	if err != nil {
+		t.Fatalf("fetch() error = %s", err)
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("attempts = %d, want 3", got)
+	}
+}
+
+// Test_fetch_RetryTerminal checks that a 4xx response other than 408/429 is
+// not retried: the server must only be hit once.
+func Test_fetch_RetryTerminal(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	fsa, _ := fsadapter.New(t.TempDir())
+	m := New(fsa, Retry(5, time.Millisecond))
+
+	err := m.fetch(context.Background(), "", request{filename: "f", url: server.URL, size: -1})
+	if err == nil {
+		t.Fatal("expected an error, got nil")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("attempts = %d, want 1 (terminal error must not be retried)", got)
+	}
+}
+
+// Test_fetch_RetryProgressStartOnce checks that a retried download, after a
+// connection drops mid-body, reports Progress.Start only once: the
+// [Retry] loop must not make a [Progress] implementation like
+// [TerminalProgress] double-count the file's size across attempts.
+func Test_fetch_RetryProgressStartOnce(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			hj, ok := w.(http.Hijacker)
+			if !ok {
+				t.Fatal("ResponseWriter does not support hijacking")
+			}
+			conn, _, err := hj.Hijack()
+			if err != nil {
+				t.Fatalf("Hijack() error = %s", err)
+			}
+			// Promise 5 bytes, deliver 2, then drop the connection: the
+			// client must see an incomplete body.
+			_, _ = conn.Write([]byte("HTTP/1.1 200 OK\r\nContent-Length: 5\r\n\r\nhe"))
+			conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = io.WriteString(w, "hello")
+	}))
+	defer server.Close()
+
+	fsa, _ := fsadapter.New(t.TempDir())
+	rp := &recordingProgress{}
+	m := New(fsa, Retry(3, time.Millisecond), WithProgress(rp))
+
+	if err := m.fetch(context.Background(), "", request{filename: "f", url: server.URL, size: -1}); err != nil {
+		t.Fatalf("fetch() error = %s", err)
+	}
+	if len(rp.starts) != 1 {
+		t.Errorf("Start called %d times, want 1", len(rp.starts))
+	}
+}
+
+// Test_RateLimit_PerHost verifies that the per-host token bucket paces
+// requests to one host to no more than perHost per second, while a second,
+// distinct host is not throttled at all.
+func Test_RateLimit_PerHost(t *testing.T) {
+	fsa, _ := fsadapter.New(t.TempDir())
+	m := New(fsa, RateLimit(2, 1))
+
+	const calls = 3
+	start := time.Now()
+	for i := 0; i < calls; i++ {
+		if err := m.hostLimiter("https://limited.example/file").Wait(context.Background()); err != nil {
+			t.Fatalf("Wait() error = %s", err)
+		}
+	}
+	elapsed := time.Since(start)
+	// 3 calls at 2 req/s with a burst of 1 must take at least 1 second: the
+	// first call drains the single token instantly, the next two each wait
+	// out a full refill interval.
+	if elapsed < time.Second {
+		t.Errorf("3 calls to limited host took %s, want >= 1s (not throttled)", elapsed)
+	}
+
+	start = time.Now()
+	if err := m.hostLimiter("https://unlimited.example/file").Wait(context.Background()); err != nil {
+		t.Fatalf("Wait() error = %s", err)
+	}
+	if got := time.Since(start); got > 100*time.Millisecond {
+		t.Errorf("first call to a different host took %s, want < 100ms (separate bucket)", got)
+	}
+}
+
+func Test_RateLimit_ZeroBurstFloored(t *testing.T) {
+	fsa, _ := fsadapter.New(t.TempDir())
+	m := New(fsa, RateLimit(5, 0))
+
+	if m.rateBurst != 1 {
+		t.Errorf("rateBurst = %d, want 1 (a burst of 0 can never let a request through)", m.rateBurst)
+	}
+	if err := m.hostLimiter("https://example.com/file").Wait(context.Background()); err != nil {
+		t.Errorf("Wait() error = %s, want nil", err)
+	}
+}
+
 func Test_parseURLs(t *testing.T) {
 	type args struct {
 		urls []string
@@ -338,8 +714,8 @@ func Test_parseURLs(t *testing.T) {
 				"https://localhost/bar.jpg",
 			}},
 			[]request{
-				{filename: "foo.txt", url: "https://example.com/foo.txt"},
-				{filename: "bar.jpg", url: "https://localhost/bar.jpg"},
+				{filename: "foo.txt", url: "https://example.com/foo.txt", size: -1},
+				{filename: "bar.jpg", url: "https://localhost/bar.jpg", size: -1},
 			},
 			false,
 		},
@@ -359,7 +735,7 @@ func Test_parseURLs(t *testing.T) {
 				"https://localhost/bar.jpg",
 			}},
 			[]request{
-				{filename: "bar.jpg", url: "https://localhost/bar.jpg"},
+				{filename: "bar.jpg", url: "https://localhost/bar.jpg", size: -1},
 			},
 			false,
 		},
@@ -0,0 +1,51 @@
+package asyncdl
+
+import "fmt"
+
+// FetchError wraps an error encountered while downloading a single URL,
+// recording enough context — which URL, which destination filename, and
+// (for HTTP failures) the response status code — for a caller to decide
+// whether to retry it.  See [DownloadErrors].
+type FetchError struct {
+	URL        string
+	Filename   string
+	StatusCode int
+	Err        error
+}
+
+func (e *FetchError) Error() string {
+	return fmt.Sprintf("fetch %s: %s", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error { return e.Err }
+
+// WriteError wraps an error returned by the file system adapter while
+// creating or writing a downloaded file.
+type WriteError struct {
+	Filename string
+	Err      error
+}
+
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("write %q: %s", e.Filename, e.Err)
+}
+
+func (e *WriteError) Unwrap() error { return e.Err }
+
+// DownloadErrors aggregates the errors encountered while downloading a
+// batch of files.  Unless [FailFast] is enabled, [Manager.Download] always
+// returns every failure this way instead of silently discarding them, so
+// that callers can use errors.As to find the [FetchError], [ChecksumError],
+// or [WriteError] of interest and decide which URLs to retry.
+type DownloadErrors []error
+
+func (e DownloadErrors) Error() string {
+	if len(e) == 1 {
+		return e[0].Error()
+	}
+	return fmt.Sprintf("%d downloads failed: %s (and %d more)", len(e), e[0], len(e)-1)
+}
+
+// Unwrap implements the Go 1.20+ multi-error convention, so errors.Is and
+// errors.As see through to every error in e.
+func (e DownloadErrors) Unwrap() []error { return e }
@@ -0,0 +1,76 @@
+package asyncdl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/rusq/fsadapter"
+)
+
+func Test_Download_DownloadErrors(t *testing.T) {
+	fsa, _ := fsadapter.New(t.TempDir())
+	defer fsa.Close()
+
+	wantErr := errors.New("boom")
+	m := Manager{
+		fsc: fsa,
+		fetchFn: func(_ context.Context, _ fsadapter.FS, _ string, name string, _ string, _ FetchOptions) error {
+			if name == "bad" {
+				return wantErr
+			}
+			return nil
+		},
+	}
+
+	err := m.Download(context.Background(), "", []string{"http://host/ok", "http://host/bad"})
+	if err == nil {
+		t.Fatal("expected a DownloadErrors, got nil")
+	}
+
+	var errs DownloadErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error is not a DownloadErrors: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Fatalf("len(errs) = %d, want 1", len(errs))
+	}
+
+	var fetchErr *FetchError
+	if !errors.As(errs[0], &fetchErr) {
+		t.Fatalf("errs[0] is not a *FetchError: %v", errs[0])
+	}
+	if fetchErr.Filename != "bad" {
+		t.Errorf("fetchErr.Filename = %q, want %q", fetchErr.Filename, "bad")
+	}
+	if !errors.Is(fetchErr, wantErr) {
+		t.Errorf("fetchErr does not wrap the original error")
+	}
+}
+
+func Test_Download_FailFast(t *testing.T) {
+	fsa, _ := fsadapter.New(t.TempDir())
+	defer fsa.Close()
+
+	m := Manager{
+		fsc:        fsa,
+		failFast:   true,
+		numWorkers: 1,
+		fetchFn: func(_ context.Context, _ fsadapter.FS, _ string, name string, _ string, _ FetchOptions) error {
+			return errors.New("nope")
+		},
+	}
+
+	err := m.Download(context.Background(), "", []string{"http://host/a.txt", "http://host/b.txt", "http://host/c.txt"})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	var errs DownloadErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("error is not a DownloadErrors: %v", err)
+	}
+	if len(errs) != 1 {
+		t.Errorf("len(errs) = %d, want 1 (FailFast should stop at the first failure)", len(errs))
+	}
+}
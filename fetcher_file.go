@@ -0,0 +1,68 @@
+package asyncdl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rusq/fsadapter"
+)
+
+// fileFetcher is the built-in [Fetcher] for file:// URLs.  It copies the
+// referenced local file as-is; [FetchOptions.Headers] are not applicable
+// and are ignored.
+type fileFetcher struct{}
+
+func (fileFetcher) Scheme() []string { return []string{"file"} }
+
+func (fileFetcher) Fetch(_ context.Context, fsa fsadapter.FS, dir, name, uri string, opts FetchOptions) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+
+	src, err := os.Open(u.Path)
+	if err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+	defer src.Close()
+
+	fp := filepath.Join(dir, name)
+	wc, err := fsa.Create(fp)
+	if err != nil {
+		return &WriteError{Filename: name, Err: err}
+	}
+	defer wc.Close()
+
+	body := progressBody(name, expectedSize(opts.Size, -1), opts.Progress, src)
+
+	var hasher hash.Hash
+	dst := io.Writer(wc)
+	if opts.SHA256 != "" {
+		hasher = sha256.New()
+		dst = io.MultiWriter(wc, hasher)
+	}
+
+	if _, err := io.Copy(dst, body); err != nil {
+		return fmt.Errorf("file: %w", err)
+	}
+
+	if hasher != nil {
+		if got := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(got, opts.SHA256) {
+			wc.Close()
+			_ = fsa.WriteFile(fp, nil, 0o644)
+			return &ChecksumError{Filename: name, Want: opts.SHA256, Got: got}
+		}
+	}
+
+	return nil
+}
+
+var _ Fetcher = fileFetcher{}
@@ -0,0 +1,216 @@
+package asyncdl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/textproto"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rusq/fsadapter"
+)
+
+// ftpFetcher is the built-in [Fetcher] for ftp:// URLs.  It speaks just
+// enough of RFC 959 to log in (anonymously, or with credentials from the
+// URL), switch to binary mode, and RETR a file over a passive-mode data
+// connection.  [FetchOptions.Headers] are not applicable and are ignored.
+type ftpFetcher struct{}
+
+func (ftpFetcher) Scheme() []string { return []string{"ftp"} }
+
+func (ftpFetcher) Fetch(ctx context.Context, fsa fsadapter.FS, dir, name, uri string, opts FetchOptions) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+
+	data, err := ftpRetr(ctx, u)
+	if err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+	defer data.Close()
+
+	fp := filepath.Join(dir, name)
+	wc, err := fsa.Create(fp)
+	if err != nil {
+		return &WriteError{Filename: name, Err: err}
+	}
+	defer wc.Close()
+
+	body := progressBody(name, opts.Size, opts.Progress, data)
+
+	var hasher hash.Hash
+	dst := io.Writer(wc)
+	if opts.SHA256 != "" {
+		hasher = sha256.New()
+		dst = io.MultiWriter(wc, hasher)
+	}
+
+	if _, err := io.Copy(dst, body); err != nil {
+		return fmt.Errorf("ftp: %w", err)
+	}
+
+	if hasher != nil {
+		if got := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(got, opts.SHA256) {
+			wc.Close()
+			_ = fsa.WriteFile(fp, nil, 0o644)
+			return &ChecksumError{Filename: name, Want: opts.SHA256, Got: got}
+		}
+	}
+
+	return nil
+}
+
+// ftpRetr logs into the FTP server addressed by u and returns a reader over
+// the requested file's data connection.  Closing the returned io.ReadCloser
+// also closes the underlying control connection.
+func ftpRetr(ctx context.Context, u *url.URL) (io.ReadCloser, error) {
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "21")
+	}
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	ctrl := textproto.NewConn(conn)
+
+	if _, _, err := ctrl.ReadResponse(220); err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+
+	user := "anonymous"
+	pass := "anonymous@"
+	if u.User != nil {
+		user = u.User.Username()
+		if p, ok := u.User.Password(); ok {
+			pass = p
+		}
+	}
+	if err := ctrlCmd(ctrl, 331, "USER %s", user); err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+	if err := ctrlCmd(ctrl, 230, "PASS %s", pass); err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+	if err := ctrlCmd(ctrl, 200, "TYPE I"); err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+
+	dataAddr, err := ftpPasv(ctrl)
+	if err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+
+	id := ctrl.Next()
+	ctrl.StartRequest(id)
+	if err := ctrl.PrintfLine("RETR %s", u.Path); err != nil {
+		ctrl.EndRequest(id)
+		ctrl.Close()
+		return nil, err
+	}
+	ctrl.EndRequest(id)
+
+	data, err := d.DialContext(ctx, "tcp", dataAddr)
+	if err != nil {
+		ctrl.Close()
+		return nil, err
+	}
+
+	ctrl.StartResponse(id)
+	if _, _, err := ctrl.ReadCodeLine(150); err != nil {
+		ctrl.EndResponse(id)
+		data.Close()
+		ctrl.Close()
+		return nil, err
+	}
+	ctrl.EndResponse(id)
+
+	return &ftpDataConn{data: data, ctrl: ctrl}, nil
+}
+
+// ftpDataConn wraps an FTP data connection so that closing it also drains
+// the final "226 Transfer complete" response and closes the control
+// connection.
+type ftpDataConn struct {
+	data net.Conn
+	ctrl *textproto.Conn
+}
+
+func (c *ftpDataConn) Read(p []byte) (int, error) { return c.data.Read(p) }
+
+func (c *ftpDataConn) Close() error {
+	dataErr := c.data.Close()
+	_, _, ctrlErr := c.ctrl.ReadResponse(226)
+	if closeErr := c.ctrl.Close(); closeErr != nil && ctrlErr == nil {
+		ctrlErr = closeErr
+	}
+	if dataErr != nil {
+		return dataErr
+	}
+	return ctrlErr
+}
+
+// ctrlCmd sends a command and requires the given status code in response.
+func ctrlCmd(ctrl *textproto.Conn, code int, format string, args ...any) error {
+	id, err := ctrl.Cmd(format, args...)
+	if err != nil {
+		return err
+	}
+	ctrl.StartResponse(id)
+	defer ctrl.EndResponse(id)
+	_, _, err = ctrl.ReadResponse(code)
+	return err
+}
+
+// ftpPasv issues PASV and parses the "h1,h2,h3,h4,p1,p2" tuple from its
+// response into a dialable host:port.
+func ftpPasv(ctrl *textproto.Conn) (string, error) {
+	id, err := ctrl.Cmd("PASV")
+	if err != nil {
+		return "", err
+	}
+	ctrl.StartResponse(id)
+	defer ctrl.EndResponse(id)
+	_, msg, err := ctrl.ReadResponse(227)
+	if err != nil {
+		return "", err
+	}
+
+	start := strings.IndexByte(msg, '(')
+	end := strings.IndexByte(msg, ')')
+	if start < 0 || end < 0 || end < start {
+		return "", fmt.Errorf("malformed PASV response: %q", msg)
+	}
+	parts := strings.Split(msg[start+1:end], ",")
+	if len(parts) != 6 {
+		return "", fmt.Errorf("malformed PASV response: %q", msg)
+	}
+	p1, err := strconv.Atoi(parts[4])
+	if err != nil {
+		return "", fmt.Errorf("malformed PASV response: %q", msg)
+	}
+	p2, err := strconv.Atoi(parts[5])
+	if err != nil {
+		return "", fmt.Errorf("malformed PASV response: %q", msg)
+	}
+	port := p1*256 + p2
+	return net.JoinHostPort(strings.Join(parts[0:4], "."), strconv.Itoa(port)), nil
+}
+
+var _ Fetcher = ftpFetcher{}
+var _ io.ReadCloser = (*ftpDataConn)(nil)
@@ -0,0 +1,83 @@
+package asyncdl
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/rusq/fsadapter"
+)
+
+// fakeFTPServer serves exactly one RETR of body over a passive data
+// connection, enough to exercise [ftpFetcher.Fetch]'s happy path.
+func fakeFTPServer(t *testing.T, body string) (addr string) {
+	t.Helper()
+
+	ctrlLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dataLn, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, dataPortStr, err := net.SplitHostPort(dataLn.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dataPort int
+	fmt.Sscanf(dataPortStr, "%d", &dataPort)
+
+	go func() {
+		conn, err := ctrlLn.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		w := bufio.NewWriter(conn)
+		r := bufio.NewReader(conn)
+
+		reply := func(line string) {
+			w.WriteString(line + "\r\n")
+			w.Flush()
+		}
+
+		reply("220 fake ftp ready")
+		r.ReadString('\n') // USER
+		reply("331 send password")
+		r.ReadString('\n') // PASS
+		reply("230 logged in")
+		r.ReadString('\n') // TYPE I
+		reply("200 type set")
+		r.ReadString('\n') // PASV
+		reply(fmt.Sprintf("227 entering passive mode (127,0,0,1,%d,%d)", dataPort/256, dataPort%256))
+
+		dataConn, err := dataLn.Accept()
+		if err != nil {
+			return
+		}
+
+		r.ReadString('\n') // RETR
+		reply("150 opening data connection")
+
+		dataConn.Write([]byte(body))
+		dataConn.Close()
+
+		reply("226 transfer complete")
+	}()
+
+	return ctrlLn.Addr().String()
+}
+
+func Test_ftpFetcher(t *testing.T) {
+	const body = "ftp file contents"
+	addr := fakeFTPServer(t, body)
+
+	fsa, _ := fsadapter.New(t.TempDir())
+	err := (ftpFetcher{}).Fetch(context.Background(), fsa, "dir", "out.txt", "ftp://"+addr+"/remote/file.txt", FetchOptions{Size: -1})
+	if err != nil {
+		t.Fatalf("Fetch() error = %s", err)
+	}
+}
@@ -0,0 +1,105 @@
+package asyncdl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rusq/fsadapter"
+)
+
+type stubFetcher struct {
+	schemes []string
+	called  bool
+}
+
+func (f *stubFetcher) Scheme() []string { return f.schemes }
+func (f *stubFetcher) Fetch(_ context.Context, _ fsadapter.FS, _, _, _ string, _ FetchOptions) error {
+	f.called = true
+	return nil
+}
+
+func Test_dispatch(t *testing.T) {
+	fsa, _ := fsadapter.New(t.TempDir())
+	m := New(fsa)
+
+	t.Run("no fetcher registered for scheme", func(t *testing.T) {
+		if err := m.dispatch(context.Background(), fsa, "", "name", "gopher://example.com/x", FetchOptions{}); err == nil {
+			t.Error("expected an error, got nil")
+		}
+	})
+
+	t.Run("registered fetcher is used", func(t *testing.T) {
+		stub := &stubFetcher{schemes: []string{"gopher"}}
+		m2 := New(fsa, RegisterFetcher(stub))
+		if err := m2.dispatch(context.Background(), fsa, "", "name", "gopher://example.com/x", FetchOptions{}); err != nil {
+			t.Fatalf("dispatch() error = %s", err)
+		}
+		if !stub.called {
+			t.Error("registered fetcher was not called")
+		}
+	})
+
+	t.Run("RegisterFetcher can override a built-in scheme", func(t *testing.T) {
+		stub := &stubFetcher{schemes: []string{"http"}}
+		m3 := New(fsa, RegisterFetcher(stub))
+		if err := m3.dispatch(context.Background(), fsa, "", "name", "http://example.com/x", FetchOptions{}); err != nil {
+			t.Fatalf("dispatch() error = %s", err)
+		}
+		if !stub.called {
+			t.Error("registered fetcher did not replace the built-in http fetcher")
+		}
+	})
+}
+
+func Test_httpFetcher(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	fsa, _ := fsadapter.New(t.TempDir())
+	err := httpFetcher{}.Fetch(context.Background(), fsa, "dir", "file", server.URL, FetchOptions{Client: http.DefaultClient, Size: -1})
+	if err != nil {
+		t.Fatalf("Fetch() error = %s", err)
+	}
+}
+
+func Test_fileFetcher(t *testing.T) {
+	const body = "file contents"
+	src := filepath.Join(t.TempDir(), "src.txt")
+	if err := os.WriteFile(src, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("copies the file", func(t *testing.T) {
+		fsa, _ := fsadapter.New(t.TempDir())
+		if err := (fileFetcher{}).Fetch(context.Background(), fsa, "dir", "out.txt", "file://"+src, FetchOptions{Size: -1}); err != nil {
+			t.Fatalf("Fetch() error = %s", err)
+		}
+	})
+
+	t.Run("verifies checksum", func(t *testing.T) {
+		sum := sha256.Sum256([]byte(body))
+		checksum := hex.EncodeToString(sum[:])
+
+		fsa, _ := fsadapter.New(t.TempDir())
+		err := (fileFetcher{}).Fetch(context.Background(), fsa, "dir", "out.txt", "file://"+src, FetchOptions{Size: -1, SHA256: checksum})
+		if err != nil {
+			t.Fatalf("Fetch() error = %s", err)
+		}
+	})
+
+	t.Run("rejects a mismatched checksum", func(t *testing.T) {
+		fsa, _ := fsadapter.New(t.TempDir())
+		err := (fileFetcher{}).Fetch(context.Background(), fsa, "dir", "out.txt", "file://"+src, FetchOptions{Size: -1, SHA256: "deadbeef"})
+		if _, ok := err.(*ChecksumError); !ok {
+			t.Fatalf("Fetch() error = %v, want *ChecksumError", err)
+		}
+	})
+}
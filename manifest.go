@@ -0,0 +1,100 @@
+package asyncdl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry describes a single file in a batch download manifest: where to get
+// it from, where to put it, and (optionally) the checksum to verify it
+// against once downloaded.
+type Entry struct {
+	// URL is the location to download the file from.
+	URL string `json:"url" yaml:"url"`
+	// Filename is the name the file should be saved under.  If empty, it is
+	// derived from the last path element of URL.
+	Filename string `json:"filename,omitempty" yaml:"filename,omitempty"`
+	// SHA256 is the expected SHA256 digest of the file, lower-case hex. If
+	// empty, the downloaded file is not verified.
+	SHA256 string `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+	// Size is the expected size of the file in bytes, or 0 if unknown.
+	Size int64 `json:"size,omitempty" yaml:"size,omitempty"`
+	// Headers carries extra request headers to send when fetching URL, e.g.
+	// Authorization for URLs requiring it.
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+}
+
+// ChecksumError is returned when a downloaded file's SHA256 digest does not
+// match the one given in its [Entry].
+type ChecksumError struct {
+	Filename string
+	Want     string
+	Got      string
+}
+
+func (e *ChecksumError) Error() string {
+	return fmt.Sprintf("checksum mismatch for %q: want %s, got %s", e.Filename, e.Want, e.Got)
+}
+
+// ParseManifestJSON parses a batch download manifest in JSON format, read
+// from r.  The manifest is expected to be a JSON array of [Entry].
+func ParseManifestJSON(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %w", err)
+	}
+	return entries, nil
+}
+
+// ParseManifestYAML parses a batch download manifest in YAML format, read
+// from r.  The manifest is expected to be a YAML sequence of [Entry].
+func ParseManifestYAML(r io.Reader) ([]Entry, error) {
+	var entries []Entry
+	if err := yaml.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("error parsing manifest: %w", err)
+	}
+	return entries, nil
+}
+
+// manifestRequests converts manifest entries into download requests.
+func manifestRequests(entries []Entry) ([]request, error) {
+	reqs := make([]request, 0, len(entries))
+	for _, e := range entries {
+		filename := e.Filename
+		if filename == "" {
+			bn, err := basename(e.URL)
+			if err != nil {
+				return nil, fmt.Errorf("entry %q: no filename given and none could be derived: %w", e.URL, err)
+			}
+			filename = bn
+		}
+		size := e.Size
+		if size == 0 {
+			size = -1
+		}
+		reqs = append(reqs, request{
+			filename: filename,
+			url:      e.URL,
+			size:     size,
+			sha256:   e.SHA256,
+			headers:  e.Headers,
+		})
+	}
+	return reqs, nil
+}
+
+// DownloadManifest downloads the files described by manifest and saves them
+// to the subdir directory within the file system adapter, verifying each
+// entry's checksum if one is given.  It otherwise behaves like
+// [Manager.Download].
+func (m *Manager) DownloadManifest(ctx context.Context, subdir string, manifest []Entry) error {
+	reqs, err := manifestRequests(manifest)
+	if err != nil {
+		return fmt.Errorf("error parsing manifest: %w", err)
+	}
+	return m.run(ctx, subdir, reqs)
+}
@@ -0,0 +1,149 @@
+package asyncdl
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/rusq/fsadapter"
+)
+
+func Test_ParseManifestJSON(t *testing.T) {
+	r := strings.NewReader(`[{"url":"http://example.com/a.txt","sha256":"abc"},{"url":"http://example.com/b.txt","filename":"c.txt"}]`)
+	got, err := ParseManifestJSON(r)
+	if err != nil {
+		t.Fatalf("ParseManifestJSON() error = %s", err)
+	}
+	want := []Entry{
+		{URL: "http://example.com/a.txt", SHA256: "abc"},
+		{URL: "http://example.com/b.txt", Filename: "c.txt"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseManifestJSON() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_ParseManifestYAML(t *testing.T) {
+	r := strings.NewReader("- url: http://example.com/a.txt\n  sha256: abc\n- url: http://example.com/b.txt\n  filename: c.txt\n")
+	got, err := ParseManifestYAML(r)
+	if err != nil {
+		t.Fatalf("ParseManifestYAML() error = %s", err)
+	}
+	want := []Entry{
+		{URL: "http://example.com/a.txt", SHA256: "abc"},
+		{URL: "http://example.com/b.txt", Filename: "c.txt"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseManifestYAML() = %+v, want %+v", got, want)
+	}
+}
+
+func Test_manifestRequests(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []Entry
+		want    []request
+		wantErr bool
+	}{
+		{
+			name: "filename derived from url",
+			entries: []Entry{
+				{URL: "http://example.com/dir/file.txt", SHA256: "abc"},
+			},
+			want: []request{
+				{filename: "file.txt", url: "http://example.com/dir/file.txt", size: -1, sha256: "abc"},
+			},
+		},
+		{
+			name: "explicit filename overrides derived one",
+			entries: []Entry{
+				{URL: "http://example.com/dir/file.txt", Filename: "renamed.txt"},
+			},
+			want: []request{
+				{filename: "renamed.txt", url: "http://example.com/dir/file.txt", size: -1},
+			},
+		},
+		{
+			name: "no filename and none derivable",
+			entries: []Entry{
+				{URL: "not a url"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "size passed through when given",
+			entries: []Entry{
+				{URL: "http://example.com/dir/file.txt", Size: 1024},
+			},
+			want: []request{
+				{filename: "file.txt", url: "http://example.com/dir/file.txt", size: 1024},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := manifestRequests(tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("manifestRequests() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("manifestRequests() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_DownloadManifest(t *testing.T) {
+	const body = "manifest payload"
+	sum := sha256.Sum256([]byte(body))
+	checksum := hex.EncodeToString(sum[:])
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	t.Run("verifies checksum on success", func(t *testing.T) {
+		dir := t.TempDir()
+		fsa, err := fsadapter.New(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := New(fsa)
+		manifest := []Entry{{URL: server.URL, Filename: "file.txt", SHA256: checksum}}
+		if err := m.DownloadManifest(context.Background(), "sub", manifest); err != nil {
+			t.Fatalf("DownloadManifest() error = %s", err)
+		}
+		got, err := os.ReadFile(filepath.Join(dir, "sub", "file.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != body {
+			t.Errorf("downloaded content = %q, want %q", got, body)
+		}
+	})
+
+	t.Run("fails on checksum mismatch", func(t *testing.T) {
+		dir := t.TempDir()
+		fsa, err := fsadapter.New(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m := New(fsa, IgnoreHTTPErrors(false))
+		manifest := []Entry{{URL: server.URL, Filename: "file.txt", SHA256: "deadbeef"}}
+		err = m.DownloadManifest(context.Background(), "sub", manifest)
+		if err == nil {
+			t.Fatal("expected an error, got nil")
+		}
+	})
+}
@@ -0,0 +1,190 @@
+package asyncdl
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Progress is implemented by callers that want to observe per-file and
+// aggregate download progress.  Methods are called concurrently from
+// multiple workers and must be safe for that.  See [NewTerminalProgress]
+// for a ready-made terminal implementation, or plug in your own (e.g. a TUI
+// or a set of Prometheus gauges) using the raw byte counts passed to
+// Advance.
+type Progress interface {
+	// Start is called once, when a file's download begins.  size is the
+	// total number of bytes expected, or -1 if it is unknown.
+	Start(filename string, size int64)
+	// Advance is called as bytes are received for filename.
+	Advance(filename string, n int64)
+	// Finish is called once a file's download completes, successfully or
+	// not.
+	Finish(filename string, err error)
+}
+
+// startOnceProgress wraps a Progress so that Start is forwarded at most
+// once, regardless of how many times it is called.  [Manager.fetch] wraps
+// the configured Progress in one of these for the lifetime of a single
+// request, so that a [Retry] retrying a failed attempt after the download
+// has already started doesn't make Progress.Start double-count the file's
+// size.
+type startOnceProgress struct {
+	Progress
+	once sync.Once
+}
+
+func (p *startOnceProgress) Start(filename string, size int64) {
+	p.once.Do(func() { p.Progress.Start(filename, size) })
+}
+
+// progressReader wraps an io.Reader, reporting every successful read to a
+// Progress as it is consumed.
+type progressReader struct {
+	r        io.Reader
+	filename string
+	progress Progress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.progress.Advance(r.filename, int64(n))
+	}
+	return n, err
+}
+
+// fileProgress tracks the state of a single in-progress download, for use
+// by [TerminalProgress].
+type fileProgress struct {
+	size int64
+	read int64
+}
+
+// TerminalProgress is a [Progress] that periodically renders an aggregate
+// throughput/ETA line, plus the list of files currently being downloaded,
+// to an io.Writer.  It is returned by [NewTerminalProgress].
+type TerminalProgress struct {
+	w        io.Writer
+	interval time.Duration
+	started  time.Time
+
+	mu     sync.Mutex
+	active map[string]*fileProgress
+	done   int64
+	total  int64
+
+	stopOnce sync.Once
+	stopC    chan struct{}
+	doneC    chan struct{}
+}
+
+// NewTerminalProgress returns a [TerminalProgress] that renders to w every
+// interval.  A non-positive interval defaults to one second.  Call
+// [TerminalProgress.Close] once the download is finished to stop the
+// background refresh goroutine.
+func NewTerminalProgress(w io.Writer, interval time.Duration) *TerminalProgress {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	p := &TerminalProgress{
+		w:        w,
+		interval: interval,
+		started:  time.Now(),
+		active:   make(map[string]*fileProgress),
+		stopC:    make(chan struct{}),
+		doneC:    make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+var _ Progress = (*TerminalProgress)(nil)
+
+func (p *TerminalProgress) Start(filename string, size int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active[filename] = &fileProgress{size: size}
+	if size > 0 {
+		p.total += size
+	}
+}
+
+func (p *TerminalProgress) Advance(filename string, n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if fp, ok := p.active[filename]; ok {
+		fp.read += n
+	}
+	p.done += n
+}
+
+func (p *TerminalProgress) Finish(filename string, _ error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.active, filename)
+}
+
+// Close stops the background refresh goroutine.  It is safe to call more
+// than once.
+func (p *TerminalProgress) Close() {
+	p.stopOnce.Do(func() { close(p.stopC) })
+	<-p.doneC
+}
+
+func (p *TerminalProgress) run() {
+	defer close(p.doneC)
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.stopC:
+			return
+		case <-t.C:
+			p.render()
+		}
+	}
+}
+
+func (p *TerminalProgress) render() {
+	p.mu.Lock()
+	elapsed := time.Since(p.started)
+	done, total := p.done, p.total
+	active := make([]string, 0, len(p.active))
+	for name := range p.active {
+		active = append(active, name)
+	}
+	p.mu.Unlock()
+	sort.Strings(active)
+
+	var throughput float64
+	if elapsed > 0 {
+		throughput = float64(done) / elapsed.Seconds()
+	}
+
+	eta := "unknown"
+	if total > 0 && throughput > 0 && done < total {
+		eta = (time.Duration(float64(total-done)/throughput) * time.Second).Round(time.Second).String()
+	}
+
+	fmt.Fprintf(p.w, "%s downloaded, %s/s, ETA %s, active: %s\n",
+		formatBytes(done), formatBytes(int64(throughput)), eta, strings.Join(active, ", "))
+}
+
+// formatBytes renders n bytes in the usual binary-prefixed, human readable
+// form (e.g. "1.5MiB").
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
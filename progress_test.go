@@ -0,0 +1,91 @@
+package asyncdl
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordingProgress struct {
+	starts  []string
+	advance int64
+	finish  []string
+}
+
+func (p *recordingProgress) Start(filename string, size int64) { p.starts = append(p.starts, filename) }
+func (p *recordingProgress) Advance(filename string, n int64)  { p.advance += n }
+func (p *recordingProgress) Finish(filename string, err error) { p.finish = append(p.finish, filename) }
+
+func Test_progressReader(t *testing.T) {
+	rp := &recordingProgress{}
+	r := &progressReader{r: strings.NewReader("hello world"), filename: "file", progress: rp}
+
+	buf := make([]byte, 5)
+	n, err := r.Read(buf)
+	if err != nil {
+		t.Fatalf("Read() error = %s", err)
+	}
+	if n != 5 {
+		t.Fatalf("Read() n = %d, want 5", n)
+	}
+	if rp.advance != 5 {
+		t.Errorf("advance = %d, want 5", rp.advance)
+	}
+}
+
+func Test_expectedSize(t *testing.T) {
+	tests := []struct {
+		name          string
+		prefetched    int64
+		contentLength int64
+		want          int64
+	}{
+		{"prefers prefetched", 100, 50, 100},
+		{"falls back to content length", -1, 50, 50},
+		{"unknown both", -1, -1, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := expectedSize(tt.prefetched, tt.contentLength); got != tt.want {
+				t.Errorf("expectedSize() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_TerminalProgress(t *testing.T) {
+	var buf bytes.Buffer
+	p := NewTerminalProgress(&buf, time.Millisecond)
+	defer p.Close()
+
+	p.Start("file", 100)
+	p.Advance("file", 50)
+	p.Finish("file", nil)
+
+	time.Sleep(10 * time.Millisecond)
+	p.Close()
+
+	if buf.Len() == 0 {
+		t.Error("expected at least one rendered progress line")
+	}
+}
+
+func Test_formatBytes(t *testing.T) {
+	tests := []struct {
+		name string
+		n    int64
+		want string
+	}{
+		{"bytes", 512, "512B"},
+		{"kibibytes", 2048, "2.0KiB"},
+		{"mebibytes", 5 * 1024 * 1024, "5.0MiB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatBytes(tt.n); got != tt.want {
+				t.Errorf("formatBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+//go:build s3
+
+// Package s3fetch provides an [asyncdl.Fetcher] for s3:// URLs, built on
+// the AWS SDK for Go v2.  It lives in its own nested module
+// (github.com/rusq/asyncdl/s3fetch) so that the AWS SDK dependency is
+// opt-in: importing github.com/rusq/asyncdl alone never pulls it in.  The
+// "s3" build tag additionally excludes this file from a plain
+// `go build ./...` of this module:
+//
+//	go build -tags s3 ./...
+package s3fetch
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/rusq/asyncdl"
+	"github.com/rusq/fsadapter"
+)
+
+// Fetcher is an [asyncdl.Fetcher] for s3://bucket/key URLs.  Register it
+// with [asyncdl.RegisterFetcher]:
+//
+//	cfg, _ := config.LoadDefaultConfig(ctx)
+//	m := asyncdl.New(fsa, asyncdl.RegisterFetcher(s3fetch.New(s3.NewFromConfig(cfg))))
+type Fetcher struct {
+	client *s3.Client
+}
+
+// New returns a Fetcher that downloads objects using client.
+func New(client *s3.Client) *Fetcher {
+	return &Fetcher{client: client}
+}
+
+var _ asyncdl.Fetcher = (*Fetcher)(nil)
+
+// Scheme reports the URL scheme this Fetcher handles: "s3".
+func (f *Fetcher) Scheme() []string { return []string{"s3"} }
+
+// Fetch downloads the object referenced by uri (s3://bucket/key) into
+// dir/name within fsa.
+func (f *Fetcher) Fetch(ctx context.Context, fsa fsadapter.FS, dir, name, uri string, opts asyncdl.FetchOptions) error {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("s3: %w", err)
+	}
+	bucket := u.Host
+	key := strings.TrimPrefix(u.Path, "/")
+
+	out, err := f.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("s3: %w", err)
+	}
+	defer out.Body.Close()
+
+	fp := filepath.Join(dir, name)
+	wc, err := fsa.Create(fp)
+	if err != nil {
+		return &asyncdl.WriteError{Filename: name, Err: err}
+	}
+	defer wc.Close()
+
+	size := opts.Size
+	if size < 0 && out.ContentLength != nil {
+		size = *out.ContentLength
+	}
+	body := io.Reader(out.Body)
+	if opts.Progress != nil {
+		opts.Progress.Start(name, size)
+		body = &progressReader{r: body, name: name, progress: opts.Progress}
+	}
+
+	var hasher hash.Hash
+	dst := io.Writer(wc)
+	if opts.SHA256 != "" {
+		hasher = sha256.New()
+		dst = io.MultiWriter(wc, hasher)
+	}
+
+	if _, err := io.Copy(dst, body); err != nil {
+		return fmt.Errorf("s3: %w", err)
+	}
+
+	if hasher != nil {
+		if got := hex.EncodeToString(hasher.Sum(nil)); !strings.EqualFold(got, opts.SHA256) {
+			wc.Close()
+			_ = fsa.WriteFile(fp, nil, 0o644)
+			return &asyncdl.ChecksumError{Filename: name, Want: opts.SHA256, Got: got}
+		}
+	}
+
+	return nil
+}
+
+// progressReader reports every successful read to a [asyncdl.Progress] as
+// it is consumed.  It duplicates [asyncdl]'s unexported progressReader,
+// since this package only has access to asyncdl's public API.
+type progressReader struct {
+	r        io.Reader
+	name     string
+	progress asyncdl.Progress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.progress.Advance(r.name, int64(n))
+	}
+	return n, err
+}